@@ -0,0 +1,542 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	reedsolomon "github.com/townmi/qrcode/reedsolomon"
+)
+
+// DecodedQR is the result of successfully decoding a QR code bitmap.
+type DecodedQR struct {
+	// Content is the decoded payload.
+	Content string
+
+	// Level is the error correction level read from the symbol's format
+	// info.
+	Level RecoveryLevel
+
+	// VersionNumber is the symbol version (1-40), derived from the
+	// bitmap's module count.
+	VersionNumber int
+
+	// Mask is the data mask pattern (0-7) read from the symbol's format
+	// info.
+	Mask int
+}
+
+// formatInfoMaskXOR is XORed over the raw 15-bit format info field before
+// the 5 data bits (level + mask) can be read; it exists so the symbol's
+// format info never reads as all-zero, which would look like a blank
+// module region to a scanner.
+const formatInfoMaskXOR = 0x5412
+
+// formatInfoLevel maps the 2-bit error correction level field of the format
+// info to a RecoveryLevel.
+var formatInfoLevel = map[uint]RecoveryLevel{
+	0x1: Low,
+	0x0: Medium,
+	0x3: High,
+	0x2: Highest,
+}
+
+// Decode reads a canonical, axis-aligned QR code bitmap and returns its
+// decoded content. img is expected to be exactly one pixel per module (as
+// produced by (*QRCode).Image(-1)); Decode does not attempt perspective
+// correction or finder-pattern search across a photographed scene.
+//
+// Decode un-masks the data region with the mask pattern read from the
+// symbol's format info, deinterleaves the codeword blocks for the symbol's
+// version, and Reed-Solomon-corrects each block before parsing the
+// resulting byte stream back into its encoded segments.
+func Decode(img image.Image) (*DecodedQR, error) {
+	bitmap, size, err := readBitmap(img)
+	if err != nil {
+		return nil, err
+	}
+
+	// (*QRCode).Image includes the symbol's 4-module quiet zone by default
+	// (DisableBorder is false unless the caller set it), so the bitmap
+	// Decode receives usually has a blank border around the actual symbol.
+	// Strip it before inferring the version from the module count.
+	bitmap, size = stripQuietZone(bitmap, size)
+
+	version := (size - 17) / 4
+	if version < 1 || version > 40 || size != version*4+17 {
+		return nil, fmt.Errorf("qrcode: image size %dx%d is not a valid QR code dimension", size, size)
+	}
+
+	level, mask, err := readFormatInfo(bitmap)
+	if err != nil {
+		return nil, err
+	}
+
+	qrVersion, err := qrCodeVersionForNumber(version, level)
+	if err != nil {
+		return nil, err
+	}
+
+	unmasked := unmaskBitmap(bitmap, size, mask)
+
+	bits := readDataBits(unmasked, size, version)
+	bits = bits[:len(bits)-qrVersion.numRemainderBits]
+
+	codewords := bitsToBytes(bits)
+
+	data, err := deinterleaveAndCorrect(codewords, qrVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := decodeSegments(data, encoderTypeForVersion(version))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DecodedQR{
+		Content:       content,
+		Level:         level,
+		VersionNumber: version,
+		Mask:          mask,
+	}, nil
+}
+
+// Verify renders q and decodes the rendering back, failing if the decoded
+// content doesn't match the original. It exists so the package's own test
+// suite (and callers fuzzing their own inputs) can sanity-check a generated
+// symbol without shelling out to an external scanner such as zbar or
+// libqrencode.
+func (q *QRCode) Verify() error {
+	img := q.Image(-1)
+
+	decoded, err := Decode(img)
+	if err != nil {
+		return fmt.Errorf("qrcode: verify: %w", err)
+	}
+
+	if decoded.Content != q.Content {
+		return fmt.Errorf("qrcode: verify: decoded content %q does not match original %q", decoded.Content, q.Content)
+	}
+
+	return nil
+}
+
+// readBitmap converts img into a [][]bool of dark modules, thresholding on
+// luminance, and returns the (square) module count.
+func readBitmap(img image.Image) ([][]bool, int, error) {
+	bounds := img.Bounds()
+	size := bounds.Dx()
+	if size != bounds.Dy() {
+		return nil, 0, errors.New("qrcode: image is not square")
+	}
+
+	bitmap := make([][]bool, size)
+	for y := 0; y < size; y++ {
+		row := make([]bool, size)
+		for x := 0; x < size; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			luminance := (299*r + 587*g + 114*b) / 1000
+			row[x] = luminance < 0x8000
+		}
+		bitmap[y] = row
+	}
+
+	return bitmap, size, nil
+}
+
+// quietZoneSize is the number of blank border modules (*QRCode).Image draws
+// on each side of the symbol when DisableBorder is false, matching
+// qrCodeVersion.quietZoneSize.
+const quietZoneSize = 4
+
+// stripQuietZone removes bitmap's quiet zone, if it has one, and returns the
+// inner symbol bitmap along with its (smaller) size. It detects the quiet
+// zone by checking whether the outermost quietZoneSize rings of modules are
+// entirely blank, which is true of a real quiet zone and never true of the
+// symbol itself (its corners always hold a finder pattern). Bitmaps with no
+// quiet zone, e.g. from a QRCode built with DisableBorder, are returned
+// unchanged.
+func stripQuietZone(bitmap [][]bool, size int) ([][]bool, int) {
+	if size <= 2*quietZoneSize {
+		return bitmap, size
+	}
+
+	for y := 0; y < quietZoneSize; y++ {
+		for x := 0; x < size; x++ {
+			if bitmap[y][x] || bitmap[size-1-y][x] || bitmap[x][y] || bitmap[x][size-1-y] {
+				return bitmap, size
+			}
+		}
+	}
+
+	innerSize := size - 2*quietZoneSize
+	inner := make([][]bool, innerSize)
+	for y := range inner {
+		inner[y] = bitmap[y+quietZoneSize][quietZoneSize : quietZoneSize+innerSize]
+	}
+
+	return inner, innerSize
+}
+
+// readFormatInfo reads the copy of the format info surrounding the top-left
+// finder pattern and returns the recovery level and mask pattern it
+// encodes. It trusts that copy verbatim: the redundant second copy is not
+// read and no error correction is applied; Decode's primary use case is
+// verifying symbols this package itself generated.
+func readFormatInfo(bitmap [][]bool) (RecoveryLevel, int, error) {
+	var raw uint
+
+	// Horizontal copy beside the top-left finder pattern, skipping the
+	// timing module at column 6.
+	for _, x := range []int{0, 1, 2, 3, 4, 5, 7, 8} {
+		raw <<= 1
+		if bitmap[8][x] {
+			raw |= 1
+		}
+	}
+	for _, y := range []int{7, 5, 4, 3, 2, 1, 0} {
+		raw <<= 1
+		if bitmap[y][8] {
+			raw |= 1
+		}
+	}
+
+	raw ^= formatInfoMaskXOR
+
+	levelBits := (raw >> 13) & 0x3
+	maskBits := int((raw >> 10) & 0x7)
+
+	level, ok := formatInfoLevel[levelBits]
+	if !ok {
+		return 0, 0, errors.New("qrcode: could not read format info")
+	}
+
+	return level, maskBits, nil
+}
+
+// unmaskBitmap XORs mask out of every data-region module of bitmap, leaving
+// function modules untouched.
+func unmaskBitmap(bitmap [][]bool, size, mask int) [][]bool {
+	maskFn := maskFunction(mask)
+
+	result := make([][]bool, size)
+	for y := 0; y < size; y++ {
+		row := make([]bool, size)
+		copy(row, bitmap[y])
+		for x := 0; x < size; x++ {
+			if !isFunctionModule(size, x, y) && maskFn(x, y) {
+				row[x] = !row[x]
+			}
+		}
+		result[y] = row
+	}
+
+	return result
+}
+
+// maskFunction returns the data-mask predicate for mask pattern id (0-7),
+// per ISO/IEC 18004 table 10. A module is inverted when the predicate
+// returns true.
+func maskFunction(mask int) func(x, y int) bool {
+	switch mask {
+	case 0:
+		return func(x, y int) bool { return (x+y)%2 == 0 }
+	case 1:
+		return func(x, y int) bool { return y%2 == 0 }
+	case 2:
+		return func(x, y int) bool { return x%3 == 0 }
+	case 3:
+		return func(x, y int) bool { return (x+y)%3 == 0 }
+	case 4:
+		return func(x, y int) bool { return (y/2+x/3)%2 == 0 }
+	case 5:
+		return func(x, y int) bool { return (x*y)%2+(x*y)%3 == 0 }
+	case 6:
+		return func(x, y int) bool { return ((x*y)%2+(x*y)%3)%2 == 0 }
+	default:
+		return func(x, y int) bool { return ((x+y)%2+(x*y)%3)%2 == 0 }
+	}
+}
+
+// readDataBits walks the data region in the same up/down zigzag column
+// order the encoder used to place bits, skipping function modules, and
+// returns the bits it finds in that order.
+func readDataBits(bitmap [][]bool, size, version int) []bool {
+	var bits []bool
+
+	goingUp := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 { // skip the vertical timing pattern column
+			right--
+		}
+
+		if goingUp {
+			for y := size - 1; y >= 0; y-- {
+				for _, x := range []int{right, right - 1} {
+					if isFunctionModule(size, x, y) {
+						continue
+					}
+					bits = append(bits, bitmap[y][x])
+				}
+			}
+		} else {
+			for y := 0; y < size; y++ {
+				for _, x := range []int{right, right - 1} {
+					if isFunctionModule(size, x, y) {
+						continue
+					}
+					bits = append(bits, bitmap[y][x])
+				}
+			}
+		}
+
+		goingUp = !goingUp
+	}
+
+	return bits
+}
+
+// bitsToBytes packs bits, most-significant bit first, into bytes, dropping
+// any trailing partial byte.
+func bitsToBytes(bits []bool) []byte {
+	n := len(bits) / 8
+	out := make([]byte, n)
+	for i := 0; i < n; i++ {
+		var b byte
+		for j := 0; j < 8; j++ {
+			b <<= 1
+			if bits[i*8+j] {
+				b |= 1
+			}
+		}
+		out[i] = b
+	}
+	return out
+}
+
+// deinterleaveAndCorrect splits the interleaved codeword stream back into
+// per-block data+EC codewords (mirroring (*QRCode).encodeBlocks in
+// reverse), Reed-Solomon corrects each block, and concatenates the
+// corrected data codewords in block order.
+func deinterleaveAndCorrect(codewords []byte, version *qrCodeVersion) ([]byte, error) {
+	type blockInfo struct {
+		numData int
+		numEC   int
+		data    []byte
+		ec      []byte
+	}
+
+	var blocks []*blockInfo
+	for _, g := range version.block {
+		for j := 0; j < g.numBlocks; j++ {
+			blocks = append(blocks, &blockInfo{
+				numData: g.numDataCodewords,
+				numEC:   g.numCodewords - g.numDataCodewords,
+			})
+		}
+	}
+
+	idx := 0
+	for working := true; working; {
+		working = false
+		for _, b := range blocks {
+			if len(b.data) >= b.numData {
+				continue
+			}
+			b.data = append(b.data, codewords[idx])
+			idx++
+			working = true
+		}
+	}
+
+	for working := true; working; {
+		working = false
+		for _, b := range blocks {
+			if len(b.ec) >= b.numEC {
+				continue
+			}
+			b.ec = append(b.ec, codewords[idx])
+			idx++
+			working = true
+		}
+	}
+
+	var result []byte
+	for _, b := range blocks {
+		corrected, err := reedsolomon.Decode(append(append([]byte{}, b.data...), b.ec...), b.numEC)
+		if err != nil {
+			return nil, fmt.Errorf("qrcode: block error correction failed: %w", err)
+		}
+		result = append(result, corrected...)
+	}
+
+	return result, nil
+}
+
+// encoderTypeForVersion returns which of the three version bands (1-9,
+// 10-26, 27-40) version falls into, to size character count indicators the
+// same way the encoder did.
+func encoderTypeForVersion(version int) dataEncoderType {
+	switch {
+	case version <= 9:
+		return dataEncoderType1To9
+	case version <= 26:
+		return dataEncoderType10To26
+	default:
+		return dataEncoderType27To40
+	}
+}
+
+// decodeSegments parses the data codeword stream back into its encoded
+// segments and concatenates their text. t gives the symbol's version band,
+// used to size each mode's character count indicator the same way the
+// encoder did. Numeric, Alphanumeric, and byte segments are decoded; Kanji
+// segments are not supported for decoding (New's encoder only ever produces
+// one when called with ModeKanji, so ordinary auto-segmented content never
+// hits this). Structured Append and ECI headers are skipped over rather
+// than interpreted.
+func decodeSegments(data []byte, t dataEncoderType) (string, error) {
+	bits := bytesToBits(data)
+
+	var content []byte
+	pos := 0
+
+	for pos+4 <= len(bits) {
+		mode := readBitsAsUint(bits, pos, 4)
+		pos += 4
+
+		switch mode {
+		case 0x0: // terminator
+			return string(content), nil
+
+		case 0x3: // Structured Append header: index(4) + count-1(4) + parity(8)
+			pos += 16
+
+		case 0x7: // ECI: skip the (1, 2, or 3 byte) designator, fall through to byte mode
+			if pos >= len(bits) {
+				return "", errors.New("qrcode: truncated ECI designator")
+			}
+			if !bits[pos] {
+				pos += 8
+			} else if pos+1 < len(bits) && !bits[pos+1] {
+				pos += 16
+			} else {
+				pos += 24
+			}
+
+		case 0x1: // numeric mode
+			countBits := numericCharCountIndicatorBits(t)
+			if pos+countBits > len(bits) {
+				return "", errors.New("qrcode: truncated character count indicator")
+			}
+			count := readBitsAsUint(bits, pos, countBits)
+			pos += countBits
+
+			for remaining := count; remaining > 0; {
+				group := remaining
+				if group > 3 {
+					group = 3
+				}
+				groupBits := 1 + 3*int(group)
+				if pos+groupBits > len(bits) {
+					return "", errors.New("qrcode: truncated numeric-mode data")
+				}
+				value := readBitsAsUint(bits, pos, groupBits)
+				pos += groupBits
+
+				digits := fmt.Sprintf("%0*d", group, value)
+				content = append(content, digits...)
+				remaining -= group
+			}
+
+		case 0x2: // alphanumeric mode
+			countBits := alphanumericCharCountIndicatorBits(t)
+			if pos+countBits > len(bits) {
+				return "", errors.New("qrcode: truncated character count indicator")
+			}
+			count := readBitsAsUint(bits, pos, countBits)
+			pos += countBits
+
+			for remaining := count; remaining > 0; {
+				group := remaining
+				if group > 2 {
+					group = 2
+				}
+				groupBits := 6
+				if group == 2 {
+					groupBits = 11
+				}
+				if pos+groupBits > len(bits) {
+					return "", errors.New("qrcode: truncated alphanumeric-mode data")
+				}
+				value := readBitsAsUint(bits, pos, groupBits)
+				pos += groupBits
+
+				if group == 2 {
+					content = append(content, decodeAlphanumericCharacter(value/45))
+					content = append(content, decodeAlphanumericCharacter(value%45))
+				} else {
+					content = append(content, decodeAlphanumericCharacter(value))
+				}
+				remaining -= group
+			}
+
+		case 0x4: // byte mode
+			byteCountBits := byteCharCountIndicatorBits(t)
+			if pos+byteCountBits > len(bits) {
+				return "", errors.New("qrcode: truncated character count indicator")
+			}
+			count := readBitsAsUint(bits, pos, byteCountBits)
+			pos += byteCountBits
+
+			for i := uint(0); i < count; i++ {
+				if pos+8 > len(bits) {
+					return "", errors.New("qrcode: truncated byte-mode data")
+				}
+				content = append(content, byte(readBitsAsUint(bits, pos, 8)))
+				pos += 8
+			}
+
+		default:
+			return "", fmt.Errorf("qrcode: unsupported mode indicator %#x for decoding", mode)
+		}
+	}
+
+	return string(content), nil
+}
+
+// decodeAlphanumericCharacter is the inverse of encodeAlphanumericCharacter,
+// mapping a 0-44 alphanumeric mode value back to its QR Code character.
+func decodeAlphanumericCharacter(v uint) byte {
+	switch {
+	case v <= 9:
+		return byte(v) + '0'
+	case v <= 35:
+		return byte(v-10) + 'A'
+	default:
+		const punctuation = " $%*+-./:"
+		return punctuation[v-36]
+	}
+}
+
+func bytesToBits(data []byte) []bool {
+	bits := make([]bool, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, b&(1<<uint(i)) != 0)
+		}
+	}
+	return bits
+}
+
+func readBitsAsUint(bits []bool, start, numBits int) uint {
+	var v uint
+	for i := 0; i < numBits; i++ {
+		v <<= 1
+		if bits[start+i] {
+			v |= 1
+		}
+	}
+	return v
+}
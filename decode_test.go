@@ -0,0 +1,226 @@
+package qrcode
+
+import (
+	"image"
+	"testing"
+)
+
+func TestVerifyRoundTrip(t *testing.T) {
+	levels := []RecoveryLevel{Low, Medium, High, Highest}
+
+	contents := []string{
+		"https://example.com/",
+		"a short message",
+		// Long enough to require a higher-version symbol (16-bit byte-mode
+		// character count indicator), which is the case the format-info and
+		// byte-count-indicator width bugs only showed up on.
+		"This is a considerably longer payload intended to push the chosen QR code version well past 9, exercising the 10-26 and 27-40 version bands and their wider character count indicators and block layouts during round-trip decoding.",
+	}
+
+	for _, level := range levels {
+		for _, content := range contents {
+			q, err := New(content, level)
+			if err != nil {
+				t.Fatalf("New(%q, %v): %v", content, level, err)
+			}
+
+			if err := q.Verify(); err != nil {
+				t.Errorf("Verify() for version %d level %v: %v", q.VersionNumber, level, err)
+			}
+		}
+	}
+}
+
+func TestVerifyRoundTripWithECI(t *testing.T) {
+	content := "héllo wörld"
+
+	q, err := New(content, Highest, ModeByteWithECI(26))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := q.Verify(); err != nil {
+		t.Errorf("Verify() with ECI hint: %v", err)
+	}
+}
+
+func TestStructuredAppendSymbolsVerify(t *testing.T) {
+	content := "This content is split across several linked Structured Append symbols, each of which must still Verify() on its own: the decoder's mode-0x3 case exists specifically to skip each chunk's Structured Append header before parsing the rest of that chunk's segments."
+
+	codes, err := NewStructuredAppend(content, Medium, 40)
+	if err != nil {
+		t.Fatalf("NewStructuredAppend: %v", err)
+	}
+
+	if len(codes) < minStructuredAppendSymbols {
+		t.Fatalf("NewStructuredAppend returned %d symbols, want at least %d", len(codes), minStructuredAppendSymbols)
+	}
+
+	for i, q := range codes {
+		if err := q.Verify(); err != nil {
+			t.Errorf("symbol %d/%d: Verify(): %v", i+1, len(codes), err)
+		}
+	}
+}
+
+func TestVerifyRoundTripNumericAndAlphanumeric(t *testing.T) {
+	// New's encoder auto-segments content as Numeric or Alphanumeric mode
+	// whenever that's more compact than byte mode, so ordinary numeric and
+	// alphanumeric content (phone numbers, order IDs, tracking codes)
+	// exercises decodeSegments' 0x1/0x2 mode cases, not just byte mode.
+	contents := []string{
+		"4155551234",
+		"09876543210123456789",
+		"ORDER-REF 2024/06/30:99.50",
+	}
+
+	for _, content := range contents {
+		q, err := New(content, Medium)
+		if err != nil {
+			t.Fatalf("New(%q): %v", content, err)
+		}
+
+		if err := q.Verify(); err != nil {
+			t.Errorf("Verify() for %q: %v", content, err)
+		}
+	}
+}
+
+func TestVerifyRoundTripQuietZone(t *testing.T) {
+	content := "hello world test content only letters"
+
+	withBorder, err := New(content, Medium)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := withBorder.Verify(); err != nil {
+		t.Errorf("Verify() with default (bordered) quiet zone: %v", err)
+	}
+
+	withoutBorder, err := New(content, Medium)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	withoutBorder.DisableBorder = true
+	if err := withoutBorder.Verify(); err != nil {
+		t.Errorf("Verify() with DisableBorder=true: %v", err)
+	}
+}
+
+// dataModuleCoordinates returns the data-region module coordinates in the
+// same zigzag column order readDataBits walks them in, so a test can target
+// a specific codeword by indexing 8 consecutive entries (coords[8*i:8*i+8]).
+func dataModuleCoordinates(size int) [][2]int {
+	var coords [][2]int
+
+	goingUp := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+
+		if goingUp {
+			for y := size - 1; y >= 0; y-- {
+				for _, x := range []int{right, right - 1} {
+					if isFunctionModule(size, x, y) {
+						continue
+					}
+					coords = append(coords, [2]int{x, y})
+				}
+			}
+		} else {
+			for y := 0; y < size; y++ {
+				for _, x := range []int{right, right - 1} {
+					if isFunctionModule(size, x, y) {
+						continue
+					}
+					coords = append(coords, [2]int{x, y})
+				}
+			}
+		}
+
+		goingUp = !goingUp
+	}
+
+	return coords
+}
+
+// flipModule inverts the rendered pixel for the data module at (x, y) in a
+// symbol-sized coordinate space (i.e. before adding the quiet zone), which
+// flips exactly the one underlying data bit at that position regardless of
+// the symbol's mask pattern, since unmasking is just a per-module XOR.
+func flipModule(img *image.Paletted, quietZoneSize, x, y int) {
+	offset := img.PixOffset(x+quietZoneSize, y+quietZoneSize)
+	img.Pix[offset] = uint8((int(img.Pix[offset]) + 1) % len(img.Palette))
+}
+
+func TestDecodeCorrectsErrorsWithinBudget(t *testing.T) {
+	content := "short"
+
+	q, err := New(content, Highest)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	img, ok := q.Image(-1).(*image.Paletted)
+	if !ok {
+		t.Fatalf("Image(-1) = %T, want *image.Paletted", q.Image(-1))
+	}
+
+	coords := dataModuleCoordinates(q.symbol.symbolSize)
+
+	// Flip one bit in each of 3 distinct codewords, well within the single
+	// block's numECCodewords/2 correction budget (8 codewords, for version
+	// 1 at Level Highest).
+	for _, bit := range []int{0, 8, 16} {
+		flipModule(img, q.symbol.quietZoneSize, coords[bit][0], coords[bit][1])
+	}
+
+	decoded, err := Decode(img)
+	if err != nil {
+		t.Fatalf("Decode() with 3 corrupted codewords: %v", err)
+	}
+	if decoded.Content != content {
+		t.Errorf("Decode() with 3 corrupted codewords: content = %q, want %q", decoded.Content, content)
+	}
+}
+
+func TestDecodeFailsCleanlyBeyondBudget(t *testing.T) {
+	content := "short"
+
+	q, err := New(content, Highest)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	img, ok := q.Image(-1).(*image.Paletted)
+	if !ok {
+		t.Fatalf("Image(-1) = %T, want *image.Paletted", q.Image(-1))
+	}
+
+	coords := dataModuleCoordinates(q.symbol.symbolSize)
+
+	// Flip one bit in each of 16 distinct codewords, well beyond the single
+	// block's numECCodewords/2 correction budget (8 codewords, for version
+	// 1 at Level Highest).
+	for i := 0; i < 16; i++ {
+		bit := i * 8
+		flipModule(img, q.symbol.quietZoneSize, coords[bit][0], coords[bit][1])
+	}
+
+	if _, err := Decode(img); err == nil {
+		t.Error("Decode() with more corrupted codewords than the block's Reed-Solomon budget: expected an error, got nil")
+	}
+}
+
+func TestDecodeRejectsWrongSize(t *testing.T) {
+	q, err := New("not a valid symbol size", Medium)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	img := q.Image(500) // upscaled, no longer 1px per module
+	if _, err := Decode(img); err == nil {
+		t.Error("Decode() on a non-canonical image size: expected an error, got nil")
+	}
+}
@@ -0,0 +1,173 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// DefaultEmbedCoverage is the fraction of the module area EmbedImage
+// obscures with a logo when coverage is 0. It's only a starting point:
+// embedLogoInto clamps the actual square to whatever the chosen version's
+// weakest Reed-Solomon block can still correct, so the realized coverage
+// may come out smaller than this.
+const DefaultEmbedCoverage = 0.15
+
+// MaxEmbedCoverage is the largest coverage EmbedImage allows. It's a ceiling
+// on top of the Reed-Solomon-derived clamp in embedLogoInto, not a guarantee
+// that a symbol survives losing this much of its area.
+const MaxEmbedCoverage = 0.30
+
+// EmbedImage composites logo onto the center of the QR code the next time
+// it's rendered with Image or PNG, sized so it obscures at most coverage
+// fraction of the module area (DefaultEmbedCoverage if coverage is 0). It
+// only allows this at Level Highest, the only recovery level with enough
+// Reed-Solomon budget to safely lose a logo-sized region of the symbol.
+func (q *QRCode) EmbedImage(logo image.Image, coverage float64) error {
+	if logo == nil {
+		return errors.New("qrcode: logo must not be nil")
+	}
+
+	if q.Level != Highest {
+		return errors.New("qrcode: EmbedImage requires Level == Highest to retain enough Reed-Solomon budget to recover the obscured modules")
+	}
+
+	if coverage == 0 {
+		coverage = DefaultEmbedCoverage
+	}
+	if coverage <= 0 || coverage > MaxEmbedCoverage {
+		return fmt.Errorf("qrcode: coverage must be between 0 and %.2f", MaxEmbedCoverage)
+	}
+
+	if q.version.maxSafeLogoModules() < 1 {
+		return errors.New("qrcode: this content's version leaves no Reed-Solomon budget for a logo")
+	}
+
+	q.embeddedLogo = logo
+	q.embeddedCoverage = coverage
+
+	return nil
+}
+
+// maxSafeLogoModules returns the side length, in modules, of the largest
+// square embedLogoInto can overwrite without exceeding the weakest
+// Reed-Solomon block's correction budget. reedsolomon.Decode can correct up
+// to numECCodewords/2 erroneous codewords per block; since the QR zigzag
+// module placement scatters a block's codewords across the whole matrix
+// rather than laying them out contiguously, a single overwritten module can
+// corrupt a codeword all on its own, so the budget is counted in modules
+// one-for-one with codewords rather than the 8 modules a codeword's bits
+// would occupy if it were stored contiguously. Interleaving also means a
+// contiguous overwritten region can land disproportionately on the block
+// with the least EC budget, so that block is what bounds the whole symbol.
+func (v qrCodeVersion) maxSafeLogoModules() int {
+	minCorrectable := -1
+	for _, b := range v.block {
+		correctable := (b.numCodewords - b.numDataCodewords) / 2
+		if minCorrectable == -1 || correctable < minCorrectable {
+			minCorrectable = correctable
+		}
+	}
+	if minCorrectable < 0 {
+		return 0
+	}
+	return int(math.Sqrt(float64(minCorrectable)))
+}
+
+// embedLogoInto composites q.embeddedLogo onto a copy of src, an already
+// fully-rendered size x size QR code image with modules module rows/
+// columns. The logo is scaled to fit the largest square that fits within
+// the coverage budget, backed by a rounded white rectangle so it reads
+// cleanly against the surrounding modules.
+func (q *QRCode) embedLogoInto(src *image.Paletted, size, modules int) image.Image {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	sideModules := int(math.Floor(math.Sqrt(q.embeddedCoverage) * float64(modules)))
+	if safe := q.version.maxSafeLogoModules(); sideModules > safe {
+		sideModules = safe
+	}
+	if sideModules < 1 {
+		return dst
+	}
+
+	sidePixels := sideModules * size / modules
+	if sidePixels < 1 {
+		return dst
+	}
+	origin := (size - sidePixels) / 2
+	radius := float64(sidePixels) / 8
+
+	logoBounds := q.embeddedLogo.Bounds()
+
+	// Paint the full reserved square white first, so the sharp corners
+	// outside the rounded region show white backing instead of the QR
+	// modules underneath; the loop below only overwrites the rounded
+	// region with the logo itself.
+	for y := 0; y < sidePixels; y++ {
+		for x := 0; x < sidePixels; x++ {
+			dst.Set(origin+x, origin+y, color.White)
+		}
+	}
+
+	for y := 0; y < sidePixels; y++ {
+		srcY := logoBounds.Min.Y + y*logoBounds.Dy()/sidePixels
+		for x := 0; x < sidePixels; x++ {
+			if !insideRoundedSquare(x, y, sidePixels, radius) {
+				continue
+			}
+
+			srcX := logoBounds.Min.X + x*logoBounds.Dx()/sidePixels
+			dst.Set(origin+x, origin+y, overlayOnWhite(q.embeddedLogo.At(srcX, srcY)))
+		}
+	}
+
+	return dst
+}
+
+// overlayOnWhite flattens c onto a white backing, since the reserved region
+// is painted white before the logo itself and a fully transparent logo
+// pixel should show through as white rather than black.
+func overlayOnWhite(c color.Color) color.Color {
+	r, g, b, a := c.RGBA()
+	if a == 0xffff {
+		return c
+	}
+
+	// channel is already alpha-premultiplied (per the image/color.Color
+	// contract), so compositing onto an opaque white backing is just
+	// adding white's contribution for the remaining (1-alpha) fraction,
+	// not multiplying through by alpha a second time.
+	blend := func(channel uint32) uint8 {
+		v := channel + (0xffff - a)
+		return uint8(v >> 8)
+	}
+
+	return color.RGBA{R: blend(r), G: blend(g), B: blend(b), A: 0xff}
+}
+
+// insideRoundedSquare reports whether (x, y) falls within a side x side
+// square with corners rounded to radius.
+func insideRoundedSquare(x, y, side int, radius float64) bool {
+	fx, fy, fside := float64(x), float64(y), float64(side)
+
+	var cx, cy float64
+	switch {
+	case fx < radius && fy < radius:
+		cx, cy = radius, radius
+	case fx >= fside-radius && fy < radius:
+		cx, cy = fside-radius, radius
+	case fx < radius && fy >= fside-radius:
+		cx, cy = radius, fside-radius
+	case fx >= fside-radius && fy >= fside-radius:
+		cx, cy = fside-radius, fside-radius
+	default:
+		return true
+	}
+
+	dx, dy := fx-cx, fy-cy
+	return dx*dx+dy*dy <= radius*radius
+}
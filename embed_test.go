@@ -0,0 +1,91 @@
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEmbedImageRequiresLevelHighest(t *testing.T) {
+	q, err := New("embed level check", Medium)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	if err := q.EmbedImage(logo, 0); err == nil {
+		t.Error("EmbedImage() at Level Medium: expected an error, got nil")
+	}
+}
+
+func TestEmbedLogoIntoPaintsCornersWhite(t *testing.T) {
+	q, err := New("embed corner backing check", Highest)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	logo := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	pix := logo.Pix
+	for i := 0; i < len(pix); i += 4 {
+		pix[i], pix[i+1], pix[i+2], pix[i+3] = 0, 0, 0, 0xff // opaque black
+	}
+	if err := q.EmbedImage(logo, DefaultEmbedCoverage); err != nil {
+		t.Fatalf("EmbedImage: %v", err)
+	}
+
+	// A fully dark src simulates the worst case: every module under the
+	// reserved square is dark, so any pixel embedLogoInto leaves unpainted
+	// shows up as non-white.
+	const size = 100
+	p := color.Palette{color.White, color.Black}
+	src := image.NewPaletted(image.Rect(0, 0, size, size), p)
+	for i := range src.Pix {
+		src.Pix[i] = uint8(p.Index(color.Black))
+	}
+
+	dst := q.embedLogoInto(src, size, size)
+
+	sideModules := int(math.Floor(math.Sqrt(DefaultEmbedCoverage) * size))
+	if safe := q.version.maxSafeLogoModules(); sideModules > safe {
+		sideModules = safe
+	}
+	origin := (size - sideModules) / 2
+
+	// The reserved square's top-left corner pixel falls outside the
+	// rounded logo region; it must show the white backing painted by
+	// embedLogoInto, not the dark module underneath.
+	if got := color.RGBAModel.Convert(dst.At(origin, origin)).(color.RGBA); got != (color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}) {
+		t.Errorf("corner pixel = %+v, want opaque white", got)
+	}
+}
+
+func TestEmbedImageStaysDecodable(t *testing.T) {
+	contents := []string{
+		"short",
+		strings.Repeat("a longer payload that pushes the symbol to a bigger version ", 3),
+	}
+
+	for _, content := range contents {
+		for _, coverage := range []float64{DefaultEmbedCoverage, MaxEmbedCoverage} {
+			q, err := New(content, Highest)
+			if err != nil {
+				t.Fatalf("New(%.8q): %v", content, err)
+			}
+
+			logo := image.NewRGBA(image.Rect(0, 0, 16, 16))
+			pix := logo.Pix
+			for i := 0; i < len(pix); i += 4 {
+				pix[i], pix[i+1], pix[i+2], pix[i+3] = 0, 0, 0, 0xff // opaque black
+			}
+			if err := q.EmbedImage(logo, coverage); err != nil {
+				t.Fatalf("EmbedImage(coverage=%.2f) on %.8q: %v", coverage, content, err)
+			}
+
+			if err := q.Verify(); err != nil {
+				t.Errorf("Verify() on %.8q with a logo at coverage=%.2f: %v", content, coverage, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package qrcode
+
+import "fmt"
+
+// isFunctionModule reports whether (x, y) in a size x size symbol is part
+// of a fixed function pattern (finder, separator, timing, format info,
+// version info, alignment, or the dark module) rather than the data
+// region. Decode uses this to know which modules to skip when unmasking
+// and when walking the data region's zigzag scan order.
+func isFunctionModule(size, x, y int) bool {
+	version := (size - 17) / 4
+
+	if isFinderZoneModule(x, y, size) {
+		return true
+	}
+
+	if x == 6 || y == 6 {
+		return true // timing patterns
+	}
+
+	if isFormatInfoModule(x, y, size) {
+		return true
+	}
+
+	if version >= 7 && isVersionInfoModule(x, y, size) {
+		return true
+	}
+
+	if isAlignmentPatternModule(x, y, version) {
+		return true
+	}
+
+	if x == 8 && y == size-8 {
+		return true // dark module
+	}
+
+	return false
+}
+
+// isFinderZoneModule reports whether (x, y) falls in one of the three 8x8
+// corner zones occupied by a finder pattern plus its separator.
+func isFinderZoneModule(x, y, size int) bool {
+	if x < 8 && y < 8 {
+		return true
+	}
+	if x >= size-8 && y < 8 {
+		return true
+	}
+	if x < 8 && y >= size-8 {
+		return true
+	}
+	return false
+}
+
+// isFormatInfoModule reports whether (x, y) holds one of the two redundant
+// copies of the 15-bit format info.
+func isFormatInfoModule(x, y, size int) bool {
+	if y == 8 && (x <= 8 || x >= size-8) {
+		return true
+	}
+	if x == 8 && (y <= 8 || y >= size-7) {
+		return true
+	}
+	return false
+}
+
+// isVersionInfoModule reports whether (x, y) holds one of the two 6x3
+// version info blocks present in versions 7 and above.
+func isVersionInfoModule(x, y, size int) bool {
+	if x < 6 && y >= size-11 && y <= size-9 {
+		return true
+	}
+	if y < 6 && x >= size-11 && x <= size-9 {
+		return true
+	}
+	return false
+}
+
+// isAlignmentPatternModule reports whether (x, y) falls within a 5x5
+// alignment pattern for the given version, skipping the three center
+// combinations that would overlap a finder pattern.
+func isAlignmentPatternModule(x, y, version int) bool {
+	centers := alignmentPatternCenters(version)
+	if len(centers) == 0 {
+		return false
+	}
+
+	first := centers[0]
+	last := centers[len(centers)-1]
+
+	for _, cy := range centers {
+		for _, cx := range centers {
+			if (cx == first && cy == first) || (cx == first && cy == last) || (cx == last && cy == first) {
+				continue
+			}
+			if absInt(x-cx) <= 2 && absInt(y-cy) <= 2 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// alignmentPatternCenters returns the row/column coordinates (shared
+// between the two axes) of alignment pattern centers for version, per
+// ISO/IEC 18004 Annex E. Version 1 has no alignment patterns.
+func alignmentPatternCenters(version int) []int {
+	if version < 1 || version > 40 {
+		return nil
+	}
+	return alignmentPatternTable[version-1]
+}
+
+var alignmentPatternTable = [40][]int{
+	{},
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+	{6, 30, 54},
+	{6, 32, 58},
+	{6, 34, 62},
+	{6, 26, 46, 66},
+	{6, 26, 48, 70},
+	{6, 26, 50, 74},
+	{6, 30, 54, 78},
+	{6, 30, 56, 82},
+	{6, 30, 58, 86},
+	{6, 34, 62, 90},
+	{6, 28, 50, 72, 94},
+	{6, 26, 50, 74, 98},
+	{6, 30, 54, 78, 102},
+	{6, 28, 54, 80, 106},
+	{6, 32, 58, 84, 110},
+	{6, 30, 58, 86, 114},
+	{6, 34, 62, 90, 118},
+	{6, 26, 50, 74, 98, 122},
+	{6, 30, 54, 78, 102, 126},
+	{6, 26, 52, 78, 104, 130},
+	{6, 30, 56, 82, 108, 134},
+	{6, 34, 60, 86, 112, 138},
+	{6, 30, 58, 86, 114, 142},
+	{6, 34, 62, 90, 118, 146},
+	{6, 30, 54, 78, 102, 126, 150},
+	{6, 24, 50, 76, 102, 128, 154},
+	{6, 28, 54, 80, 106, 132, 158},
+	{6, 32, 58, 84, 110, 136, 162},
+	{6, 26, 54, 82, 110, 138, 166},
+	{6, 30, 58, 86, 114, 142, 170},
+}
+
+// qrCodeVersionForNumber looks up the capacity/block-layout table entry for
+// the given version and recovery level, the same table chooseQRCodeVersion
+// consults when picking a version by encoded length.
+func qrCodeVersionForNumber(version int, level RecoveryLevel) (*qrCodeVersion, error) {
+	for i := range versions {
+		v := &versions[i]
+		if v.version == version && v.level == level {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("qrcode: no version table entry for version %d at level %v", version, level)
+}
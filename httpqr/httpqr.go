@@ -0,0 +1,209 @@
+// Package httpqr serves QR codes for arbitrary content over HTTP, so
+// callers don't need to re-implement the same glue for login flows,
+// file-transfer share links, and MFA enrollment pages.
+package httpqr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/townmi/qrcode"
+)
+
+// Options configures Handler and Encode.
+type Options struct {
+	// DefaultLevel is used when the request doesn't specify a level. The
+	// zero value falls back to qrcode.Low.
+	DefaultLevel qrcode.RecoveryLevel
+
+	// MaxTextLength caps the length of the text parameter. The zero value
+	// falls back to 2048 bytes.
+	MaxTextLength int
+
+	// MaxSize caps the requested image size, in pixels. The zero value
+	// falls back to 1024.
+	MaxSize int
+
+	// RateLimit caps requests per second served by a Handler, shared
+	// across all callers. The zero value means unlimited.
+	RateLimit int
+}
+
+const (
+	defaultMaxTextLength = 2048
+	defaultMaxSize       = 1024
+
+	// defaultSize is used when the request doesn't specify a size. It's
+	// deliberately much smaller than defaultMaxSize, which only bounds how
+	// large a caller may explicitly ask for.
+	defaultSize = 256
+)
+
+func (o *Options) setDefaults() {
+	if o.MaxTextLength == 0 {
+		o.MaxTextLength = defaultMaxTextLength
+	}
+	if o.MaxSize == 0 {
+		o.MaxSize = defaultMaxSize
+	}
+	// DefaultLevel's zero value is qrcode.Low, already a sensible default
+	// for a handler that may be serving a high volume of requests.
+}
+
+// Handler returns an http.Handler that serves QR codes for arbitrary
+// content. The payload is read from the "text" query parameter, or from the
+// request path if "text" is absent. "level" (L/M/Q/H), "size" (pixels), and
+// "format" (png/svg/txt) are all optional query parameters; format can also
+// be negotiated from the Accept header.
+func Handler(opts Options) http.Handler {
+	opts.setDefaults()
+	limiter := newRateLimiter(opts.RateLimit)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.Allow() {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		content := requestContent(r)
+		if content == "" {
+			http.Error(w, "missing text parameter", http.StatusBadRequest)
+			return
+		}
+
+		Encode(w, r, content, opts)
+	})
+}
+
+// Encode writes content as a QR code to w, honoring the same "level",
+// "size", and "format" query parameters and Accept-header negotiation as
+// Handler. It's exposed separately so callers who already have their own
+// router and payload extraction can mount QR rendering without going
+// through Handler.
+func Encode(w http.ResponseWriter, r *http.Request, content string, opts Options) {
+	opts.setDefaults()
+
+	if len(content) > opts.MaxTextLength {
+		http.Error(w, "text parameter exceeds the maximum allowed length", http.StatusBadRequest)
+		return
+	}
+
+	level := parseLevel(r.URL.Query().Get("level"), opts.DefaultLevel)
+	size := parseSize(r.URL.Query().Get("size"), opts.MaxSize)
+	format := negotiateFormat(r)
+
+	etag := computeETag(content, level, size, format)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", etag)
+	// format can be negotiated from Accept when the "format" query
+	// parameter is absent, so a shared cache must not serve one client's
+	// cached response format to another client with a different Accept.
+	w.Header().Set("Vary", "Accept")
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	q, err := qrcode.New(content, level)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch format {
+	case "svg":
+		body, err := q.SVG(size)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(body)
+
+	case "txt":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, q.ToSmallString(false))
+
+	default:
+		body, err := q.PNG(size)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(body)
+	}
+}
+
+// requestContent extracts the payload to encode from the "text" query
+// parameter, falling back to the request path (minus its leading slash)
+// for callers who prefer to mount the handler at e.g. /qr/<content>.
+func requestContent(r *http.Request) string {
+	if text := r.URL.Query().Get("text"); text != "" {
+		return text
+	}
+	return strings.TrimPrefix(r.URL.Path, "/")
+}
+
+func parseLevel(s string, fallback qrcode.RecoveryLevel) qrcode.RecoveryLevel {
+	switch strings.ToUpper(s) {
+	case "L":
+		return qrcode.Low
+	case "M":
+		return qrcode.Medium
+	case "Q":
+		return qrcode.High
+	case "H":
+		return qrcode.Highest
+	default:
+		return fallback
+	}
+}
+
+// parseSize returns the requested size, capped at max, or defaultSize if
+// the request didn't specify one. max only bounds an explicit request; it
+// is not itself the default, or every unsized request would render at the
+// largest permitted size.
+func parseSize(s string, max int) int {
+	size, err := strconv.Atoi(s)
+	if err != nil || size <= 0 {
+		return defaultSize
+	}
+	if size > max {
+		return max
+	}
+	return size
+}
+
+// negotiateFormat picks an output format from the "format" query parameter,
+// falling back to the Accept header, and defaulting to PNG.
+func negotiateFormat(r *http.Request) string {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "svg", "png", "txt":
+		return strings.ToLower(r.URL.Query().Get("format"))
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "image/svg+xml"):
+		return "svg"
+	case strings.Contains(accept, "text/plain"):
+		return "txt"
+	default:
+		return "png"
+	}
+}
+
+// computeETag derives a stable ETag from the encoded request parameters, so
+// identical requests share a cache entry.
+func computeETag(content string, level qrcode.RecoveryLevel, size int, format string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%d|%d|%s", content, level, size, format)
+	return `"` + hex.EncodeToString(h.Sum(nil))[:16] + `"`
+}
@@ -0,0 +1,190 @@
+package httpqr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qrcode "github.com/townmi/qrcode"
+)
+
+func TestEncodeDefaultsToPNG(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	Encode(w, r, "hello", Options{})
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	if resp.Header.Get("ETag") == "" {
+		t.Error("ETag header not set")
+	}
+}
+
+func TestEncodeFormatFromQueryParam(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?format=svg", nil)
+	w := httptest.NewRecorder()
+
+	Encode(w, r, "hello", Options{})
+
+	resp := w.Result()
+	if got := resp.Header.Get("Content-Type"); got != "image/svg+xml" {
+		t.Errorf("Content-Type = %q, want image/svg+xml", got)
+	}
+}
+
+func TestEncodeFormatFromAcceptHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/plain")
+	w := httptest.NewRecorder()
+
+	Encode(w, r, "hello", Options{})
+
+	resp := w.Result()
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain prefix", got)
+	}
+}
+
+func TestEncodeRejectsOversizedText(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	Encode(w, r, strings.Repeat("a", 10), Options{MaxTextLength: 5})
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestEncodeHonorsIfNoneMatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	Encode(w, r, "hello", Options{})
+	etag := w.Result().Header.Get("ETag")
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+	Encode(w2, r2, "hello", Options{})
+
+	if got := w2.Result().StatusCode; got != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", got, http.StatusNotModified)
+	}
+}
+
+func TestHandlerReadsTextFromQueryAndPath(t *testing.T) {
+	handler := Handler(Options{})
+
+	r := httptest.NewRequest(http.MethodGet, "/?text=hello", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Errorf("query param: status = %d, want %d", w.Result().StatusCode, http.StatusOK)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/hello-world", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Errorf("path: status = %d, want %d", w.Result().StatusCode, http.StatusOK)
+	}
+}
+
+func TestHandlerRejectsMissingText(t *testing.T) {
+	handler := Handler(Options{})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got := w.Result().StatusCode; got != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", got, http.StatusBadRequest)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want qrcode.RecoveryLevel
+	}{
+		{"L", qrcode.Low},
+		{"M", qrcode.Medium},
+		{"Q", qrcode.High},
+		{"H", qrcode.Highest},
+		{"q", qrcode.High},
+		{"bogus", qrcode.Medium},
+		{"", qrcode.Medium},
+	}
+
+	for _, test := range tests {
+		if got := parseLevel(test.in, qrcode.Medium); got != test.want {
+			t.Errorf("parseLevel(%q, Medium) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want int
+	}{
+		{"", 1024, defaultSize},
+		{"not a number", 1024, defaultSize},
+		{"0", 1024, defaultSize},
+		{"-5", 1024, defaultSize},
+		{"512", 1024, 512},
+		{"2048", 1024, 1024},
+	}
+
+	for _, test := range tests {
+		if got := parseSize(test.in, test.max); got != test.want {
+			t.Errorf("parseSize(%q, %d) = %d, want %d", test.in, test.max, got, test.want)
+		}
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		query  string
+		accept string
+		want   string
+	}{
+		{"format=svg", "", "svg"},
+		{"format=bogus", "image/svg+xml", "svg"},
+		{"", "image/svg+xml", "svg"},
+		{"", "text/plain", "txt"},
+		{"", "", "png"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/?"+test.query, nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+		if got := negotiateFormat(r); got != test.want {
+			t.Errorf("negotiateFormat(query=%q, accept=%q) = %q, want %q", test.query, test.accept, got, test.want)
+		}
+	}
+}
+
+func TestComputeETagVariesOnFormat(t *testing.T) {
+	a := computeETag("hello", qrcode.Medium, 256, "png")
+	b := computeETag("hello", qrcode.Medium, 256, "svg")
+	if a == b {
+		t.Error("computeETag should differ when format differs")
+	}
+
+	c := computeETag("hello", qrcode.Medium, 256, "png")
+	if a != c {
+		t.Error("computeETag should be stable for identical inputs")
+	}
+}
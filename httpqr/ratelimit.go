@@ -0,0 +1,59 @@
+package httpqr
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a simple token bucket shared across every request a
+// Handler serves. It exists so Options.RateLimit doesn't need an external
+// dependency.
+type rateLimiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	maxTokens  float64
+	refillRate float64 // tokens per second
+	last       time.Time
+	unlimited  bool
+}
+
+// newRateLimiter returns a rateLimiter allowing up to ratePerSecond
+// requests per second, bursting up to one second's worth of tokens. A
+// ratePerSecond of 0 means unlimited.
+func newRateLimiter(ratePerSecond int) *rateLimiter {
+	if ratePerSecond <= 0 {
+		return &rateLimiter{unlimited: true}
+	}
+	return &rateLimiter{
+		tokens:     float64(ratePerSecond),
+		maxTokens:  float64(ratePerSecond),
+		refillRate: float64(ratePerSecond),
+		last:       time.Now(),
+	}
+}
+
+// Allow reports whether a request may proceed, consuming a token if so.
+func (l *rateLimiter) Allow() bool {
+	if l.unlimited {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+
+	l.tokens += elapsed * l.refillRate
+	if l.tokens > l.maxTokens {
+		l.tokens = l.maxTokens
+	}
+
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
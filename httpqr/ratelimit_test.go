@@ -0,0 +1,25 @@
+package httpqr
+
+import "testing"
+
+func TestRateLimiterUnlimitedByDefault(t *testing.T) {
+	l := newRateLimiter(0)
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%d: want true for an unlimited limiter", i)
+		}
+	}
+}
+
+func TestRateLimiterCapsBurstAtMaxTokens(t *testing.T) {
+	l := newRateLimiter(3)
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%d: want true within the initial burst", i)
+		}
+	}
+	if l.Allow() {
+		t.Error("Allow() after exhausting the burst: want false")
+	}
+}
@@ -0,0 +1,178 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+
+	bitset "github.com/townmi/qrcode/bitset"
+)
+
+// Mode indicators per ISO/IEC 18004 table 2.
+const (
+	modeIndicatorByte  = 0x4 // 0100
+	modeIndicatorECI   = 0x7 // 0111
+	modeIndicatorKanji = 0x8 // 1000
+)
+
+// qrEncodingMode selects which segment encoding EncodingHint forces New to
+// use instead of its default auto-segmenting path.
+type qrEncodingMode int
+
+const (
+	encodingModeAuto qrEncodingMode = iota
+	encodingModeKanji
+	encodingModeByteECI
+)
+
+// EncodingHint forces New to encode content with a specific mode instead of
+// auto-segmenting it. Construct one with ModeKanji or
+// ModeByteWithECI.
+type EncodingHint struct {
+	mode          qrEncodingMode
+	eciDesignator uint32
+}
+
+// ModeKanji forces Kanji (Shift-JIS) encoding of content. content must
+// already be Shift-JIS encoded bytes, not UTF-8.
+var ModeKanji = EncodingHint{mode: encodingModeKanji}
+
+// ModeByteWithECI forces byte-mode encoding tagged with the given ECI
+// designator (e.g. 26 for UTF-8), which many mobile scanners require in
+// order to decode non-ASCII payloads correctly instead of guessing at the
+// character set.
+func ModeByteWithECI(designator uint32) EncodingHint {
+	return EncodingHint{mode: encodingModeByteECI, eciDesignator: designator}
+}
+
+// kanjiCharCountIndicatorBits gives the width, in bits, of Kanji mode's
+// character count indicator for the given version band (1-9, 10-26, 27-40).
+func kanjiCharCountIndicatorBits(t dataEncoderType) int {
+	switch t {
+	case dataEncoderType1To9:
+		return 8
+	case dataEncoderType10To26:
+		return 10
+	default:
+		return 12
+	}
+}
+
+// byteCharCountIndicatorBits gives the width, in bits, of byte mode's
+// character count indicator for the given version band.
+func byteCharCountIndicatorBits(t dataEncoderType) int {
+	if t == dataEncoderType1To9 {
+		return 8
+	}
+	return 16
+}
+
+// numericCharCountIndicatorBits gives the width, in bits, of numeric mode's
+// character count indicator for the given version band.
+func numericCharCountIndicatorBits(t dataEncoderType) int {
+	switch t {
+	case dataEncoderType1To9:
+		return 10
+	case dataEncoderType10To26:
+		return 12
+	default:
+		return 14
+	}
+}
+
+// alphanumericCharCountIndicatorBits gives the width, in bits, of
+// alphanumeric mode's character count indicator for the given version band.
+func alphanumericCharCountIndicatorBits(t dataEncoderType) int {
+	switch t {
+	case dataEncoderType1To9:
+		return 9
+	case dataEncoderType10To26:
+		return 11
+	default:
+		return 13
+	}
+}
+
+// encodeKanji packs Shift-JIS encoded text, two bytes per character, into
+// Kanji mode's 13-bit-per-character representation, prefixed with the mode
+// indicator and character count indicator.
+func encodeKanji(t dataEncoderType, data []byte) (*bitset.Bitset, error) {
+	if len(data)%2 != 0 {
+		return nil, errors.New("kanji data must be an even number of Shift-JIS bytes")
+	}
+
+	numChars := len(data) / 2
+
+	result := bitset.New()
+	result.AppendByte(modeIndicatorKanji, 4)
+	result.AppendUint32(uint32(numChars), kanjiCharCountIndicatorBits(t))
+
+	for i := 0; i < len(data); i += 2 {
+		packed, err := packKanjiRune(data[i], data[i+1])
+		if err != nil {
+			return nil, err
+		}
+		result.AppendUint32(uint32(packed), 13)
+	}
+
+	return result, nil
+}
+
+// packKanjiRune converts one Shift-JIS double-byte character into the
+// 13-bit value used by Kanji mode: subtract the block's base offset (0x8140
+// or 0xC140), split the result into high/low bytes, then combine as
+// high*0xC0 + low.
+func packKanjiRune(hi, lo byte) (uint, error) {
+	c := uint(hi)<<8 | uint(lo)
+
+	switch {
+	case c >= 0x8140 && c <= 0x9FFC:
+		c -= 0x8140
+	case c >= 0xE040 && c <= 0xEBBF:
+		c -= 0xC140
+	default:
+		return 0, fmt.Errorf("byte pair %#04x is outside the Shift-JIS Kanji range", c)
+	}
+
+	high := c >> 8
+	low := c & 0xFF
+
+	return high*0xC0 + low, nil
+}
+
+// appendECIDesignator appends an ECI designator to b in its variable-length
+// representation, per ISO/IEC 18004 Annex F: values 0-127 take a single
+// byte, 128-16383 take two bytes (10 prefix), and 16384-999999 take three
+// bytes (110 prefix).
+func appendECIDesignator(b *bitset.Bitset, designator uint32) error {
+	switch {
+	case designator <= 127:
+		b.AppendUint32(designator, 8)
+	case designator <= 16383:
+		b.AppendBools(true, false)
+		b.AppendUint32(designator, 14)
+	case designator <= 999999:
+		b.AppendBools(true, true, false)
+		b.AppendUint32(designator, 21)
+	default:
+		return fmt.Errorf("ECI designator %d is out of range", designator)
+	}
+	return nil
+}
+
+// encodeByteWithECI emits the ECI mode indicator and designator ahead of a
+// normal byte-mode segment, so scanners that honor ECI decode the payload
+// using the tagged character set (e.g. ECI 26 for UTF-8) rather than
+// guessing.
+func encodeByteWithECI(t dataEncoderType, data []byte, designator uint32) (*bitset.Bitset, error) {
+	result := bitset.New()
+	result.AppendByte(modeIndicatorECI, 4)
+	if err := appendECIDesignator(result, designator); err != nil {
+		return nil, err
+	}
+
+	result.AppendByte(modeIndicatorByte, 4)
+	result.AppendUint32(uint32(len(data)), byteCharCountIndicatorBits(t))
+	result.AppendBytes(data)
+
+	return result, nil
+}
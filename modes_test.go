@@ -0,0 +1,108 @@
+package qrcode
+
+import (
+	"testing"
+
+	bitset "github.com/townmi/qrcode/bitset"
+)
+
+func TestPackKanjiRune(t *testing.T) {
+	tests := []struct {
+		hi, lo byte
+		want   uint
+	}{
+		{0x81, 0x40, 0},    // start of the first Shift-JIS Kanji block
+		{0xE0, 0x40, 5952}, // start of the second Shift-JIS Kanji block
+	}
+
+	for _, test := range tests {
+		got, err := packKanjiRune(test.hi, test.lo)
+		if err != nil {
+			t.Fatalf("packKanjiRune(%#x, %#x): %v", test.hi, test.lo, err)
+		}
+		if got != test.want {
+			t.Errorf("packKanjiRune(%#x, %#x) = %d, want %d", test.hi, test.lo, got, test.want)
+		}
+	}
+}
+
+func TestPackKanjiRuneOutOfRange(t *testing.T) {
+	if _, err := packKanjiRune(0x20, 0x20); err == nil {
+		t.Error("packKanjiRune() on a byte pair outside the Kanji ranges: expected an error, got nil")
+	}
+}
+
+func TestEncodeKanji(t *testing.T) {
+	data := []byte{0x81, 0x40, 0xE0, 0x40} // two Shift-JIS characters
+
+	encoded, err := encodeKanji(dataEncoderType1To9, data)
+	if err != nil {
+		t.Fatalf("encodeKanji: %v", err)
+	}
+
+	want := bitset.New()
+	want.AppendByte(modeIndicatorKanji, 4)
+	want.AppendUint32(2, kanjiCharCountIndicatorBits(dataEncoderType1To9))
+	want.AppendUint32(0, 13)
+	want.AppendUint32(5952, 13)
+
+	if !encoded.Equals(want) {
+		t.Errorf("encodeKanji(%x) = %s, want %s", data, encoded, want)
+	}
+}
+
+func TestEncodeKanjiRejectsOddLength(t *testing.T) {
+	if _, err := encodeKanji(dataEncoderType1To9, []byte{0x81}); err == nil {
+		t.Error("encodeKanji() with an odd number of bytes: expected an error, got nil")
+	}
+}
+
+func TestAppendECIDesignator(t *testing.T) {
+	tests := []struct {
+		designator uint32
+		wantBits   int
+	}{
+		{0, 8},
+		{127, 8},
+		{128, 16},
+		{16383, 16},
+		{16384, 24},
+		{999999, 24},
+	}
+
+	for _, test := range tests {
+		b := bitset.New()
+		if err := appendECIDesignator(b, test.designator); err != nil {
+			t.Fatalf("appendECIDesignator(%d): %v", test.designator, err)
+		}
+		if b.Len() != test.wantBits {
+			t.Errorf("appendECIDesignator(%d) appended %d bits, want %d", test.designator, b.Len(), test.wantBits)
+		}
+	}
+}
+
+func TestAppendECIDesignatorOutOfRange(t *testing.T) {
+	if err := appendECIDesignator(bitset.New(), 1000000); err == nil {
+		t.Error("appendECIDesignator(1000000): expected an error, got nil")
+	}
+}
+
+func TestEncodeByteWithECI(t *testing.T) {
+	data := []byte("hi")
+
+	encoded, err := encodeByteWithECI(dataEncoderType1To9, data, 26)
+	if err != nil {
+		t.Fatalf("encodeByteWithECI: %v", err)
+	}
+
+	want := bitset.New()
+	want.AppendByte(modeIndicatorECI, 4)
+	want.AppendUint32(26, 8)
+	want.AppendByte(modeIndicatorByte, 4)
+	want.AppendUint32(uint32(len(data)), byteCharCountIndicatorBits(dataEncoderType1To9))
+	want.AppendBytes(data)
+
+	if !encoded.Equals(want) {
+		t.Errorf("encodeByteWithECI(%q, 26) = %s, want %s", data, encoded, want)
+	}
+}
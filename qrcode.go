@@ -10,8 +10,15 @@ import (
 
 	bitset "github.com/townmi/qrcode/bitset"
 	reedsolomon "github.com/townmi/qrcode/reedsolomon"
+	terminal "github.com/townmi/qrcode/terminal"
 )
 
+// defaultTerminalQuietZone is the light-module border ToString/ToSmallString
+// add around the symbol, matching the CLI's --quiet-zone default. Without
+// it, terminal output is frequently unscannable, since decoders rely on the
+// quiet zone to find the symbol's edges.
+const defaultTerminalQuietZone = 4
+
 // Encode a QR Code and return a raw PNG image
 func Encode(content string, level RecoveryLevel, size int) ([]byte, error) {
 	var q *QRCode
@@ -39,6 +46,14 @@ type QRCode struct {
 	// Disable the QR code border
 	DisableBorder bool
 
+	// Shape controls how dark modules are rendered in SVG output. It has no
+	// effect on PNG/Image output, which always renders square modules.
+	Shape ModuleShape
+
+	// embeddedLogo and embeddedCoverage are set by EmbedImage; see embed.go.
+	embeddedLogo     image.Image
+	embeddedCoverage float64
+
 	encoder *dataEncoder
 	version qrCodeVersion
 
@@ -47,7 +62,19 @@ type QRCode struct {
 	mask   int
 }
 
-func New(content string, level RecoveryLevel) (*QRCode, error) {
+// New creates a QR code encoding content at the given recovery level. By
+// default content is auto-segmented into numeric, alphanumeric, and
+// byte-mode segments; pass ModeKanji or
+// ModeByteWithECI(designator) as hint to force a specific encoding mode
+// instead.
+func New(content string, level RecoveryLevel, hint ...EncodingHint) (*QRCode, error) {
+	var forcedMode qrEncodingMode
+	var eciDesignator uint32
+	if len(hint) > 0 {
+		forcedMode = hint[0].mode
+		eciDesignator = hint[0].eciDesignator
+	}
+
 	encoders := []dataEncoderType{dataEncoderType1To9, dataEncoderType10To26, dataEncoderType27To40}
 
 	var encoder *dataEncoder
@@ -57,7 +84,15 @@ func New(content string, level RecoveryLevel) (*QRCode, error) {
 
 	for _, t := range encoders {
 		encoder = newDataEncoder(t)
-		encoded, err = encoder.encode([]byte(content))
+
+		switch forcedMode {
+		case encodingModeKanji:
+			encoded, err = encodeKanji(t, []byte(content))
+		case encodingModeByteECI:
+			encoded, err = encodeByteWithECI(t, []byte(content), eciDesignator)
+		default:
+			encoded, err = encoder.encode([]byte(content))
+		}
 
 		if err != nil {
 			continue
@@ -183,6 +218,10 @@ func (q *QRCode) Image(size int) image.Image {
 		}
 	}
 
+	if q.embeddedLogo != nil {
+		return q.embedLogoInto(img, size, realSize)
+	}
+
 	return img
 }
 
@@ -309,55 +348,43 @@ func (q *QRCode) addPadding() {
 	}
 }
 
+// ToString renders the QR code as full-block characters, two per module,
+// with a quiet zone so the output stays scannable in dark-mode terminals.
+// It forwards to the terminal package; use terminal.TerminalWriter
+// directly for control over half-blocks, ANSI color, or custom glyphs.
 func (q *QRCode) ToString(inverseColor bool) string {
-	bits := q.Bitmap()
-	var buf bytes.Buffer
-	for y := range bits {
-		for x := range bits[y] {
-			if bits[y][x] != inverseColor {
-				buf.WriteString("  ")
-			} else {
-				buf.WriteString("██")
-			}
-		}
-		buf.WriteString("\n")
-	}
-	return buf.String()
+	return terminal.New(terminal.Options{QuietZone: defaultTerminalQuietZone}).Sprint(q.bitmapWithoutBorder(), inverseColor)
 }
 
+// ToSmallString renders the QR code using half-block characters, two
+// module rows per terminal row, with a quiet zone so the output stays
+// scannable in dark-mode terminals. It forwards to the terminal package;
+// use terminal.TerminalWriter directly for control over ANSI color or
+// custom glyphs.
 func (q *QRCode) ToSmallString(inverseColor bool) string {
-	bits := q.Bitmap()
-	var buf bytes.Buffer
-	// if there is an odd number of rows, the last one needs special treatment
-	for y := 0; y < len(bits)-1; y += 2 {
-		for x := range bits[y] {
-			if bits[y][x] == bits[y+1][x] {
-				if bits[y][x] != inverseColor {
-					buf.WriteString(" ")
-				} else {
-					buf.WriteString("█")
-				}
-			} else {
-				if bits[y][x] != inverseColor {
-					buf.WriteString("▄")
-				} else {
-					buf.WriteString("▀")
-				}
-			}
-		}
-		buf.WriteString("\n")
-	}
-	// special treatment for the last row if odd
-	if len(bits)%2 == 1 {
-		y := len(bits) - 1
-		for x := range bits[y] {
-			if bits[y][x] != inverseColor {
-				buf.WriteString(" ")
-			} else {
-				buf.WriteString("▀")
-			}
-		}
-		buf.WriteString("\n")
-	}
-	return buf.String()
+	return terminal.New(terminal.Options{QuietZone: defaultTerminalQuietZone, HalfBlocks: true}).Sprint(q.bitmapWithoutBorder(), inverseColor)
 }
+
+// bitmapWithoutBorder returns the symbol's bitmap with its own border
+// disabled, regardless of q.DisableBorder, leaving the terminal package's
+// QuietZone option as the sole source of the border in terminal output.
+// Without this, ToString/ToSmallString would stack the symbol's default
+// border underneath the terminal package's quiet zone, doubling it.
+func (q *QRCode) bitmapWithoutBorder() [][]bool {
+	prevDisableBorder := q.DisableBorder
+	prevSymbol := q.symbol
+	prevMask := q.mask
+
+	q.DisableBorder = true
+	q.symbol = nil
+
+	q.encode()
+	bitmap := q.symbol.bitmap()
+
+	q.DisableBorder = prevDisableBorder
+	q.symbol = prevSymbol
+	q.mask = prevMask
+
+	return bitmap
+}
+
@@ -6,10 +6,15 @@ import (
 	"log"
 	"os"
 	"qrcode"
+	"qrcode/terminal"
 )
 
 func main() {
 	var url string
+	var quietZone int
+	var half bool
+	var inverse bool
+	var color string
 	var err error
 	var q *qrcode.QRCode
 
@@ -23,6 +28,27 @@ func main() {
 				Usage:       "Load configuiration file",
 				Destination: &url,
 			},
+			&cli.IntFlag{
+				Name:        "quiet-zone",
+				Value:       4,
+				Usage:       "Width, in modules, of the light border printed around the code",
+				Destination: &quietZone,
+			},
+			&cli.BoolFlag{
+				Name:        "half",
+				Usage:       "Print two module rows per terminal row using half-block glyphs",
+				Destination: &half,
+			},
+			&cli.BoolFlag{
+				Name:        "inverse",
+				Usage:       "Swap light and dark modules, for light terminal backgrounds",
+				Destination: &inverse,
+			},
+			&cli.StringFlag{
+				Name:        "color",
+				Usage:       "Foreground color as an \"R;G;B\" triplet, e.g. \"255;0;0\"",
+				Destination: &color,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			path := ""
@@ -33,8 +59,17 @@ func main() {
 				q, err = qrcode.New(path, qrcode.Highest)
 				checkError(err)
 
-				art := q.ToString(false)
-				fmt.Println(art)
+				// Disable the symbol's own border so --quiet-zone is the
+				// sole source of the terminal output's border, instead of
+				// stacking on top of it.
+				q.DisableBorder = true
+
+				w := terminal.New(terminal.Options{
+					QuietZone:      quietZone,
+					HalfBlocks:     half,
+					ForegroundANSI: color,
+				})
+				fmt.Print(w.Sprint(q.Bitmap(), inverse))
 
 				// run qrcode
 				return nil
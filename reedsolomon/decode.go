@@ -0,0 +1,207 @@
+package reedsolomon
+
+import "errors"
+
+// Decode error-corrects codewords (data codewords followed by
+// numECCodewords error-correction codewords) over GF(2^8): it computes the
+// syndromes, runs Berlekamp-Massey to find the error locator polynomial,
+// Chien search to find the error positions, and Forney's algorithm to
+// compute the error magnitudes. It can correct up to numECCodewords/2
+// errors. On success it returns the corrected data codewords (the
+// numECCodewords suffix is dropped).
+func Decode(codewords []byte, numECCodewords int) ([]byte, error) {
+	if numECCodewords <= 0 || numECCodewords >= len(codewords) {
+		return nil, errors.New("reedsolomon: numECCodewords out of range")
+	}
+
+	elems := make([]gfElement, len(codewords))
+	for i, b := range codewords {
+		elems[i] = gfElement(b)
+	}
+
+	syndromes, hasErrors := computeSyndromes(elems, numECCodewords)
+	if !hasErrors {
+		return codewords[:len(codewords)-numECCodewords], nil
+	}
+
+	locator := berlekampMassey(syndromes)
+
+	numErrors := len(locator) - 1
+	if numErrors > numECCodewords/2 {
+		return nil, errors.New("reedsolomon: too many errors to correct")
+	}
+
+	positions := chienSearch(locator, len(elems))
+	if len(positions) != numErrors {
+		return nil, errors.New("reedsolomon: error locator has no valid roots")
+	}
+
+	if err := forneyCorrect(elems, syndromes, locator, positions); err != nil {
+		return nil, err
+	}
+
+	corrected := make([]byte, len(codewords)-numECCodewords)
+	for i := range corrected {
+		corrected[i] = byte(elems[i])
+	}
+
+	return corrected, nil
+}
+
+// computeSyndromes evaluates the received word at each of the numEC roots
+// of the generator polynomial (alpha^0 .. alpha^(numEC-1)). All-zero
+// syndromes mean the word is already a valid codeword.
+func computeSyndromes(elems []gfElement, numEC int) ([]gfElement, bool) {
+	syndromes := make([]gfElement, numEC)
+	hasErrors := false
+
+	for i := 0; i < numEC; i++ {
+		alpha := gfExpTable[i%255]
+
+		var s gfElement
+		for _, c := range elems {
+			s = gfAdd(gfMultiply(s, alpha), c)
+		}
+
+		syndromes[i] = s
+		if s != gfZero {
+			hasErrors = true
+		}
+	}
+
+	return syndromes, hasErrors
+}
+
+// berlekampMassey finds the shortest linear feedback shift register that
+// generates the syndrome sequence, i.e. the error locator polynomial
+// (coefficients, constant term first).
+func berlekampMassey(syndromes []gfElement) []gfElement {
+	n := len(syndromes)
+
+	c := make([]gfElement, n+1)
+	b := make([]gfElement, n+1)
+	c[0], b[0] = gfOne, gfOne
+
+	l := 0
+	m := 1
+	bCoeff := gfOne
+
+	for i := 0; i < n; i++ {
+		delta := syndromes[i]
+		for j := 1; j <= l; j++ {
+			delta = gfAdd(delta, gfMultiply(c[j], syndromes[i-j]))
+		}
+
+		if delta == gfZero {
+			m++
+			continue
+		}
+
+		t := make([]gfElement, len(c))
+		copy(t, c)
+
+		coef := gfDivide(delta, bCoeff)
+		for j := 0; j < len(b); j++ {
+			if j+m < len(c) {
+				c[j+m] = gfAdd(c[j+m], gfMultiply(coef, b[j]))
+			}
+		}
+
+		if 2*l <= i {
+			l = i + 1 - l
+			copy(b, t)
+			bCoeff = delta
+			m = 1
+		} else {
+			m++
+		}
+	}
+
+	return c[:l+1]
+}
+
+// chienSearch evaluates the locator polynomial at the inverse of every
+// codeword position's field element and returns the positions (0 = first
+// codeword) where it evaluates to zero, i.e. the error locations.
+func chienSearch(locator []gfElement, numCodewords int) []int {
+	var positions []int
+
+	for i := 0; i < numCodewords; i++ {
+		pos := numCodewords - 1 - i
+		xInv := gfExpTable[(255-(i%255))%255]
+
+		var y gfElement
+		xInvPow := gfOne
+		for _, coeff := range locator {
+			y = gfAdd(y, gfMultiply(coeff, xInvPow))
+			xInvPow = gfMultiply(xInvPow, xInv)
+		}
+
+		if y == gfZero {
+			positions = append(positions, pos)
+		}
+	}
+
+	return positions
+}
+
+// errorEvaluator computes Omega(x) = (S(x) * Lambda(x)) mod x^numSyndromes,
+// the polynomial used by Forney's algorithm to compute error magnitudes.
+func errorEvaluator(syndromes, locator []gfElement) []gfElement {
+	eval := make([]gfElement, len(syndromes))
+	for i, s := range syndromes {
+		for j, l := range locator {
+			if i+j < len(eval) {
+				eval[i+j] = gfAdd(eval[i+j], gfMultiply(s, l))
+			}
+		}
+	}
+	return eval
+}
+
+// locatorFormalDerivative returns the odd-indexed coefficients of locator,
+// which is the formal derivative of a GF(2^8) polynomial (even-power terms
+// vanish under XOR addition).
+func locatorFormalDerivative(locator []gfElement) []gfElement {
+	var deriv []gfElement
+	for i := 1; i < len(locator); i += 2 {
+		deriv = append(deriv, locator[i])
+	}
+	return deriv
+}
+
+// forneyCorrect computes the magnitude of the error at each position via
+// Forney's algorithm and XORs the correction into elems in place.
+func forneyCorrect(elems []gfElement, syndromes, locator []gfElement, positions []int) error {
+	errEval := errorEvaluator(syndromes, locator)
+	locatorDeriv := locatorFormalDerivative(locator)
+
+	for _, pos := range positions {
+		i := len(elems) - 1 - pos
+		xInv := gfExpTable[(255-(i%255))%255]
+		x := gfExpTable[i%255]
+
+		var evalAtXInv gfElement
+		xInvPow := gfOne
+		for _, coeff := range errEval {
+			evalAtXInv = gfAdd(evalAtXInv, gfMultiply(coeff, xInvPow))
+			xInvPow = gfMultiply(xInvPow, xInv)
+		}
+
+		var derivAtXInv gfElement
+		xInvSquaredPow := gfOne
+		for _, coeff := range locatorDeriv {
+			derivAtXInv = gfAdd(derivAtXInv, gfMultiply(coeff, xInvSquaredPow))
+			xInvSquaredPow = gfMultiply(xInvSquaredPow, gfMultiply(xInv, xInv))
+		}
+
+		if derivAtXInv == gfZero {
+			return errors.New("reedsolomon: error correction failed (zero derivative)")
+		}
+
+		magnitude := gfMultiply(x, gfDivide(evalAtXInv, derivAtXInv))
+		elems[pos] = gfAdd(elems[pos], magnitude)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	bitset "github.com/townmi/qrcode/bitset"
+)
+
+// maxStructuredAppendSymbols is the largest number of symbols the
+// Structured Append mode can chain together. The format reserves 4 bits for
+// the symbol count (encoded as count-1), capping it at 16.
+const maxStructuredAppendSymbols = 16
+
+// minStructuredAppendSymbols is the smallest useful split. A single symbol
+// has no need for Structured Append at all.
+const minStructuredAppendSymbols = 2
+
+// NewStructuredAppend partitions content across 2-16 chained QR symbols
+// using Structured Append mode, for payloads too large to fit a single
+// version-40 symbol at the requested level. Every returned *QRCode carries
+// the same 20-bit Structured Append header layout: a 4-bit mode indicator
+// (0011), a 4-bit symbol index, a 4-bit total-count-minus-1, and an 8-bit
+// parity byte XORed across the full original byte stream, so a reader can
+// detect a missing or mismatched symbol in the set before reassembling.
+//
+// maxVersion bounds how large a single symbol is allowed to grow; pass 40
+// to allow the full range.
+func NewStructuredAppend(content string, level RecoveryLevel, maxVersion int) ([]*QRCode, error) {
+	if len(content) == 0 {
+		return nil, errors.New("content is empty")
+	}
+	if len(content) < minStructuredAppendSymbols {
+		return nil, fmt.Errorf("content is too short to split into at least %d structured append symbols", minStructuredAppendSymbols)
+	}
+
+	chunks, version, encoder, err := splitForStructuredAppend(content, level, maxVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	parity := structuredAppendParity([]byte(content))
+	total := len(chunks)
+
+	codes := make([]*QRCode, total)
+	for i, chunk := range chunks {
+		q, err := newStructuredAppendSymbol(chunk, level, encoder, *version, i, total, parity)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = q
+	}
+
+	return codes, nil
+}
+
+// EncodeStructuredAppend partitions content across 2-16 linked QR symbols
+// via NewStructuredAppend and renders each as a raw PNG image.
+func EncodeStructuredAppend(content string, level RecoveryLevel, maxVersion int, size int) ([][]byte, error) {
+	codes, err := NewStructuredAppend(content, level, maxVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	pngs := make([][]byte, len(codes))
+	for i, q := range codes {
+		png, err := q.PNG(size)
+		if err != nil {
+			return nil, err
+		}
+		pngs[i] = png
+	}
+
+	return pngs, nil
+}
+
+// splitForStructuredAppend finds the smallest symbol count (2-16) and the
+// smallest version common to every resulting chunk, reserving the 20-bit
+// Structured Append header ahead of each chunk's own mode/character-count
+// indicators.
+func splitForStructuredAppend(content string, level RecoveryLevel, maxVersion int) ([]string, *qrCodeVersion, *dataEncoder, error) {
+	data := []byte(content)
+	encoderTypes := []dataEncoderType{dataEncoderType1To9, dataEncoderType10To26, dataEncoderType27To40}
+
+	for numSymbols := minStructuredAppendSymbols; numSymbols <= maxStructuredAppendSymbols; numSymbols++ {
+		chunks := splitBytesInto(data, numSymbols)
+
+		for _, t := range encoderTypes {
+			encoder := newDataEncoder(t)
+
+			lengths := make([]int, len(chunks))
+			ok := true
+			for i, chunk := range chunks {
+				encoded, err := encoder.encode(chunk)
+				if err != nil {
+					ok = false
+					break
+				}
+				lengths[i] = encoded.Len() + structuredAppendHeaderNumBits
+			}
+			if !ok {
+				continue
+			}
+
+			version := chooseCommonQRCodeVersion(level, encoder, lengths)
+			if version != nil && version.version <= maxVersion {
+				chunkStrings := make([]string, len(chunks))
+				for i, c := range chunks {
+					chunkStrings[i] = string(c)
+				}
+				return chunkStrings, version, encoder, nil
+			}
+		}
+	}
+
+	return nil, nil, nil, errors.New("content too long to encode, even with structured append")
+}
+
+// chooseCommonQRCodeVersion returns the smallest version that is able to
+// hold every one of lengths (each already including any header overhead) at
+// the given recovery level, or nil if no version (up to 40) is large enough.
+// This mirrors chooseQRCodeVersion, but since a Structured Append set
+// usually renders best as a uniform run of symbols, every chunk is checked
+// against the same candidate version rather than being sized independently.
+func chooseCommonQRCodeVersion(level RecoveryLevel, encoder *dataEncoder, lengths []int) *qrCodeVersion {
+	maxLength := 0
+	for _, l := range lengths {
+		if l > maxLength {
+			maxLength = l
+		}
+	}
+
+	return chooseQRCodeVersion(level, encoder, maxLength)
+}
+
+// splitBytesInto splits data into numParts roughly-even, non-empty
+// contiguous byte runs, in order.
+func splitBytesInto(data []byte, numParts int) [][]byte {
+	if numParts > len(data) {
+		numParts = len(data)
+	}
+
+	base := len(data) / numParts
+	extra := len(data) % numParts
+
+	chunks := make([][]byte, 0, numParts)
+	start := 0
+	for i := 0; i < numParts; i++ {
+		size := base
+		if i < extra {
+			size++
+		}
+		chunks = append(chunks, data[start:start+size])
+		start += size
+	}
+
+	return chunks
+}
+
+// structuredAppendHeaderNumBits is the width of the Structured Append
+// header: 4-bit mode indicator + 4-bit symbol index + 4-bit count-1 +
+// 8-bit parity.
+const structuredAppendHeaderNumBits = 20
+
+// newStructuredAppendSymbol encodes a single chunk and prepends its
+// Structured Append header ahead of the chunk's own mode indicator.
+func newStructuredAppendSymbol(content string, level RecoveryLevel, encoder *dataEncoder, version qrCodeVersion, index, total int, parity byte) (*QRCode, error) {
+	encoded, err := encoder.encode([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+
+	header := structuredAppendHeaderBits(index, total, parity)
+	header.Append(encoded)
+
+	q := &QRCode{
+		Content: content,
+
+		Level:         level,
+		VersionNumber: version.version,
+
+		ForegroundColor: color.Black,
+		BackgroundColor: color.White,
+
+		encoder: encoder,
+		data:    header,
+		version: version,
+	}
+
+	return q, nil
+}
+
+// structuredAppendHeaderBits returns the 20 header bits (mode indicator,
+// symbol index, total count minus 1, parity) as a Bitset, most-significant
+// bit first.
+func structuredAppendHeaderBits(index, total int, parity byte) *bitset.Bitset {
+	header := bitset.New(false, false, true, true) // mode indicator 0011
+	header.AppendByte(byte(index), 4)
+	header.AppendByte(byte(total-1), 4)
+	header.AppendByte(parity, 8)
+	return header
+}
+
+// structuredAppendParity XORs every byte of the original, unsplit content
+// together, per the Structured Append parity field.
+func structuredAppendParity(data []byte) byte {
+	var parity byte
+	for _, b := range data {
+		parity ^= b
+	}
+	return parity
+}
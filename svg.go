@@ -0,0 +1,111 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// ModuleShape selects how individual dark modules are rendered in vector
+// output. It has no effect on raster output (Image/PNG).
+type ModuleShape int
+
+const (
+	// ModuleShapeSquare renders modules as plain squares. This is the only
+	// shape that can be flattened into a single merged <path>.
+	ModuleShapeSquare ModuleShape = iota
+
+	// ModuleShapeRounded renders modules as squares with rounded corners.
+	ModuleShapeRounded
+
+	// ModuleShapeDot renders modules as circles inscribed in the module.
+	ModuleShapeDot
+)
+
+// SVG returns the QR code as a scalable SVG document. size sets the
+// width/height of the rendered document in pixels; the module grid itself is
+// always addressed in a 1-unit-per-module coordinate system, so the document
+// scales cleanly under CSS without the aliasing produced by the
+// nearest-neighbor scaling used in Image.
+func (q *QRCode) SVG(size int) ([]byte, error) {
+	var b bytes.Buffer
+	if err := q.WriteSVG(&b, size); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// WriteSVG writes the QR code to w as a scalable SVG document. See SVG for
+// details.
+func (q *QRCode) WriteSVG(w io.Writer, size int) error {
+	// Build QR code.
+	q.encode()
+
+	bitmap := q.symbol.bitmap()
+	modules := len(bitmap)
+
+	if size <= 0 {
+		size = modules
+	}
+
+	fg := colorToHex(q.ForegroundColor)
+	bg := colorToHex(q.BackgroundColor)
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">`+"\n",
+		modules, modules, size, size)
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="%s"/>`+"\n", modules, modules, bg)
+
+	switch q.Shape {
+	case ModuleShapeRounded, ModuleShapeDot:
+		writeSVGDiscreteModules(w, bitmap, q.Shape, fg)
+	default:
+		writeSVGMergedPath(w, bitmap, fg)
+	}
+
+	_, err := fmt.Fprint(w, "</svg>\n")
+	return err
+}
+
+// writeSVGMergedPath emits every dark module as a single filled <path>,
+// combining each module into its own closed "M x y h1 v1 h-1 z" subpath. A
+// single path keeps the document small even for version-40 codes, where one
+// <rect> per module would otherwise dominate the payload size.
+func writeSVGMergedPath(w io.Writer, bitmap [][]bool, fg string) {
+	var d bytes.Buffer
+	for y := range bitmap {
+		for x := range bitmap[y] {
+			if !bitmap[y][x] {
+				continue
+			}
+			fmt.Fprintf(&d, "M%d %dh1v1h-1z", x, y)
+		}
+	}
+	fmt.Fprintf(w, `<path fill="%s" d="%s"/>`+"\n", fg, d.String())
+}
+
+// writeSVGDiscreteModules emits one element per dark module. Rounded and dot
+// shapes can't be expressed as a single merged path the way plain squares
+// can, so each module is drawn individually.
+func writeSVGDiscreteModules(w io.Writer, bitmap [][]bool, shape ModuleShape, fg string) {
+	for y := range bitmap {
+		for x := range bitmap[y] {
+			if !bitmap[y][x] {
+				continue
+			}
+			switch shape {
+			case ModuleShapeDot:
+				fmt.Fprintf(w, `<circle cx="%g" cy="%g" r="0.5" fill="%s"/>`+"\n", float64(x)+0.5, float64(y)+0.5, fg)
+			case ModuleShapeRounded:
+				fmt.Fprintf(w, `<rect x="%d" y="%d" width="1" height="1" rx="0.25" ry="0.25" fill="%s"/>`+"\n", x, y, fg)
+			}
+		}
+	}
+}
+
+// colorToHex converts a color.Color to a "#rrggbb" string for use in SVG
+// fill/stroke attributes.
+func colorToHex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,90 @@
+package qrcode
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestSVGMergedPath(t *testing.T) {
+	q, err := New("svg test content", Medium)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	out, err := q.SVG(0)
+	if err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+	svg := string(out)
+
+	modules := q.symbol.size
+	if want := fmt.Sprintf(`viewBox="0 0 %d %d"`, modules, modules); !strings.Contains(svg, want) {
+		t.Errorf("SVG() = %q, want viewBox %q", svg, want)
+	}
+
+	if !strings.Contains(svg, `<path fill="#000000"`) {
+		t.Errorf("SVG() with default ModuleShapeSquare: expected a single merged <path>, got %q", svg)
+	}
+	if strings.Contains(svg, "<circle") || strings.Contains(svg, "<rect x=") {
+		t.Errorf("SVG() with default ModuleShapeSquare: unexpected discrete module element in %q", svg)
+	}
+}
+
+func TestSVGDiscreteShapes(t *testing.T) {
+	tests := []struct {
+		shape ModuleShape
+		want  string
+	}{
+		{ModuleShapeDot, "<circle"},
+		{ModuleShapeRounded, `<rect x=`},
+	}
+
+	for _, test := range tests {
+		q, err := New("svg shape test", Medium)
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		q.Shape = test.shape
+
+		out, err := q.SVG(0)
+		if err != nil {
+			t.Fatalf("SVG: %v", err)
+		}
+		svg := string(out)
+
+		if !strings.Contains(svg, test.want) {
+			t.Errorf("SVG() with Shape=%v: want an element containing %q, got %q", test.shape, test.want, svg)
+		}
+		if strings.Contains(svg, `<path fill="#000000" d="M`) {
+			t.Errorf("SVG() with Shape=%v: unexpected merged <path>, got %q", test.shape, svg)
+		}
+	}
+}
+
+func TestSVGExplicitSize(t *testing.T) {
+	q, err := New("svg size test", Medium)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	out, err := q.SVG(300)
+	if err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+	svg := string(out)
+
+	if !strings.Contains(svg, `width="300" height="300"`) {
+		t.Errorf("SVG(300) = %q, want width/height of 300", svg)
+	}
+}
+
+func TestColorToHex(t *testing.T) {
+	if got, want := colorToHex(color.Black), "#000000"; got != want {
+		t.Errorf("colorToHex(Black) = %q, want %q", got, want)
+	}
+	if got, want := colorToHex(color.White), "#ffffff"; got != want {
+		t.Errorf("colorToHex(White) = %q, want %q", got, want)
+	}
+}
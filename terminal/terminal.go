@@ -0,0 +1,173 @@
+// Package terminal renders QR code bitmaps to ANSI terminals.
+//
+// The package works directly on the [][]bool bitmaps produced by
+// (*qrcode.QRCode).Bitmap, so it has no dependency on the qrcode package
+// itself.
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Options configures a TerminalWriter.
+type Options struct {
+	// QuietZone is the number of light modules added as a border on every
+	// side, regardless of whether the bitmap's own border was disabled.
+	// QR codes without a quiet zone are frequently unscannable, since
+	// decoders use it to detect the symbol's edges.
+	QuietZone int
+
+	// HalfBlocks draws two module rows per terminal row using the
+	// half-block glyphs (▀ ▄ █ and space), halving the vertical space the
+	// code takes up.
+	HalfBlocks bool
+
+	// ForegroundANSI and BackgroundANSI, if set, are "R;G;B" decimal
+	// triplets (e.g. "255;255;255") used to emit 24-bit ANSI color escapes
+	// around the rendered bitmap.
+	ForegroundANSI string
+	BackgroundANSI string
+
+	// WhiteChar and BlackChar override the full-block glyphs, for terminals
+	// whose fonts render █ poorly. Without inverse, WhiteChar is drawn for
+	// dark modules and BlackChar for light ones (so on a dark terminal the
+	// default "██" shows the light modules); inverse swaps them. They have
+	// no effect when HalfBlocks is set.
+	WhiteChar string
+	BlackChar string
+}
+
+// TerminalWriter renders QR code bitmaps to a terminal using the options it
+// was constructed with.
+type TerminalWriter struct {
+	opts Options
+}
+
+// New returns a TerminalWriter configured with opts.
+func New(opts Options) *TerminalWriter {
+	if opts.WhiteChar == "" {
+		opts.WhiteChar = "  "
+	}
+	if opts.BlackChar == "" {
+		opts.BlackChar = "██"
+	}
+	return &TerminalWriter{opts: opts}
+}
+
+// Sprint renders bitmap to a string. inverse swaps which modules are drawn
+// light vs dark, for terminals with a light background.
+func (w *TerminalWriter) Sprint(bitmap [][]bool, inverse bool) string {
+	padded := addQuietZone(bitmap, w.opts.QuietZone)
+
+	var buf strings.Builder
+	buf.WriteString(w.colorPrefix())
+
+	if w.opts.HalfBlocks {
+		writeHalfBlocks(&buf, padded, inverse)
+	} else {
+		writeFullBlocks(&buf, padded, inverse, w.opts.WhiteChar, w.opts.BlackChar)
+	}
+
+	buf.WriteString(w.colorReset())
+
+	return buf.String()
+}
+
+// Fprint renders bitmap to out. See Sprint for the meaning of inverse.
+func (w *TerminalWriter) Fprint(out io.Writer, bitmap [][]bool, inverse bool) error {
+	_, err := io.WriteString(out, w.Sprint(bitmap, inverse))
+	return err
+}
+
+func (w *TerminalWriter) colorPrefix() string {
+	var b strings.Builder
+	if w.opts.ForegroundANSI != "" {
+		fmt.Fprintf(&b, "\x1b[38;2;%sm", w.opts.ForegroundANSI)
+	}
+	if w.opts.BackgroundANSI != "" {
+		fmt.Fprintf(&b, "\x1b[48;2;%sm", w.opts.BackgroundANSI)
+	}
+	return b.String()
+}
+
+func (w *TerminalWriter) colorReset() string {
+	if w.opts.ForegroundANSI == "" && w.opts.BackgroundANSI == "" {
+		return ""
+	}
+	return "\x1b[0m"
+}
+
+// addQuietZone returns bitmap surrounded by n rows/columns of light
+// modules on every side.
+func addQuietZone(bitmap [][]bool, n int) [][]bool {
+	if n <= 0 {
+		return bitmap
+	}
+
+	size := len(bitmap)
+	newSize := size + 2*n
+
+	result := make([][]bool, newSize)
+	for y := range result {
+		result[y] = make([]bool, newSize)
+	}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			result[y+n][x+n] = bitmap[y][x]
+		}
+	}
+
+	return result
+}
+
+// writeFullBlocks renders one glyph per module, one module row per
+// terminal row.
+func writeFullBlocks(buf *strings.Builder, bitmap [][]bool, inverse bool, whiteChar, blackChar string) {
+	for y := range bitmap {
+		for x := range bitmap[y] {
+			if bitmap[y][x] != inverse {
+				buf.WriteString(whiteChar)
+			} else {
+				buf.WriteString(blackChar)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+}
+
+// writeHalfBlocks renders two module rows per terminal row using
+// half-block glyphs, halving the vertical space the code takes up.
+func writeHalfBlocks(buf *strings.Builder, bitmap [][]bool, inverse bool) {
+	for y := 0; y < len(bitmap)-1; y += 2 {
+		for x := range bitmap[y] {
+			top := bitmap[y][x] != inverse
+			bottom := bitmap[y+1][x] != inverse
+
+			switch {
+			case top && bottom:
+				buf.WriteString(" ")
+			case !top && !bottom:
+				buf.WriteString("█")
+			case top && !bottom:
+				buf.WriteString("▄")
+			default:
+				buf.WriteString("▀")
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	if len(bitmap)%2 == 1 {
+		y := len(bitmap) - 1
+		for x := range bitmap[y] {
+			if bitmap[y][x] != inverse {
+				buf.WriteString(" ")
+			} else {
+				buf.WriteString("▀")
+			}
+		}
+		buf.WriteByte('\n')
+	}
+}
@@ -0,0 +1,123 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSprintFullBlocks(t *testing.T) {
+	bitmap := [][]bool{
+		{true, false},
+		{false, true},
+	}
+
+	w := New(Options{})
+	got := w.Sprint(bitmap, false)
+	want := "  ██\n██  \n"
+	if got != want {
+		t.Errorf("Sprint() = %q, want %q", got, want)
+	}
+}
+
+func TestSprintInverse(t *testing.T) {
+	bitmap := [][]bool{
+		{true, false},
+		{false, true},
+	}
+
+	w := New(Options{})
+	got := w.Sprint(bitmap, true)
+	want := "██  \n  ██\n"
+	if got != want {
+		t.Errorf("Sprint() with inverse=true = %q, want %q", got, want)
+	}
+}
+
+func TestSprintCustomGlyphs(t *testing.T) {
+	bitmap := [][]bool{{true, false}}
+
+	w := New(Options{WhiteChar: ".", BlackChar: "#"})
+	got := w.Sprint(bitmap, false)
+	want := ".#\n"
+	if got != want {
+		t.Errorf("Sprint() with custom glyphs = %q, want %q", got, want)
+	}
+}
+
+func TestSprintHalfBlocks(t *testing.T) {
+	bitmap := [][]bool{
+		{false, true},
+		{true, false},
+	}
+
+	w := New(Options{HalfBlocks: true})
+	got := w.Sprint(bitmap, false)
+	if !strings.Contains(got, "▄") && !strings.Contains(got, "▀") {
+		t.Errorf("Sprint() with HalfBlocks: want a half-block glyph, got %q", got)
+	}
+	if strings.Count(got, "\n") != 1 {
+		t.Errorf("Sprint() with HalfBlocks on a 2-row bitmap: want 1 terminal row, got %q", got)
+	}
+}
+
+func TestSprintHalfBlocksOddRowCount(t *testing.T) {
+	bitmap := [][]bool{
+		{false},
+		{true},
+		{false},
+	}
+
+	w := New(Options{HalfBlocks: true})
+	got := w.Sprint(bitmap, false)
+	if strings.Count(got, "\n") != 2 {
+		t.Errorf("Sprint() with HalfBlocks on a 3-row bitmap: want 2 terminal rows (1 paired + 1 trailing), got %q", got)
+	}
+}
+
+func TestSprintANSIColor(t *testing.T) {
+	bitmap := [][]bool{{true}}
+
+	w := New(Options{ForegroundANSI: "255;0;0", BackgroundANSI: "0;0;0"})
+	got := w.Sprint(bitmap, false)
+
+	if !strings.HasPrefix(got, "\x1b[38;2;255;0;0m\x1b[48;2;0;0;0m") {
+		t.Errorf("Sprint() with ANSI colors: missing color prefix, got %q", got)
+	}
+	if !strings.Contains(got, "\x1b[0m") {
+		t.Errorf("Sprint() with ANSI colors: missing reset sequence, got %q", got)
+	}
+}
+
+func TestSprintNoColorByDefault(t *testing.T) {
+	bitmap := [][]bool{{true}}
+
+	w := New(Options{})
+	got := w.Sprint(bitmap, false)
+
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("Sprint() with no ANSI options set: unexpected escape sequence in %q", got)
+	}
+}
+
+func TestAddQuietZone(t *testing.T) {
+	bitmap := [][]bool{{true}}
+
+	padded := addQuietZone(bitmap, 1)
+	if len(padded) != 3 {
+		t.Fatalf("addQuietZone(n=1) on a 1x1 bitmap: got size %d, want 3", len(padded))
+	}
+	if padded[1][1] != true {
+		t.Errorf("addQuietZone(n=1): center module = %v, want true", padded[1][1])
+	}
+	if padded[0][0] != false {
+		t.Errorf("addQuietZone(n=1): border module = %v, want false", padded[0][0])
+	}
+}
+
+func TestAddQuietZoneNoOp(t *testing.T) {
+	bitmap := [][]bool{{true, false}, {false, true}}
+
+	if got := addQuietZone(bitmap, 0); len(got) != len(bitmap) {
+		t.Errorf("addQuietZone(n=0): got size %d, want unchanged size %d", len(got), len(bitmap))
+	}
+}